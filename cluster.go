@@ -0,0 +1,185 @@
+package rcgo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// BackoffPolicy produces the wait duration to apply before the next dial
+// attempt, given how many attempts have already been made against the
+// current endpoint.
+type BackoffPolicy interface {
+	Next(attempt int) time.Duration
+}
+
+// ExponentialJitterBackoff is the default BackoffPolicy: an exponential
+// backoff capped at Max, with full jitter applied to avoid thundering-herd
+// reconnects across many clients.
+type ExponentialJitterBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Next returns Base*2^attempt, capped at Max, multiplied by a random factor
+// in [0, 1].
+func (b ExponentialJitterBackoff) Next(attempt int) time.Duration {
+	base := b.Base
+	if base <= 0 {
+		base = 200 * time.Millisecond
+	}
+
+	max := b.Max
+	if max <= 0 {
+		max = 30 * time.Second
+	}
+
+	d := base << attempt
+	if d <= 0 || d > max {
+		d = max
+	}
+
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// EndpointChangeFunc is called whenever the cluster dialer switches to a
+// different active endpoint, e.g. after a failover. It receives the new
+// endpoint's URL.
+type EndpointChangeFunc func(endpoint string)
+
+// ClusterConfig describes a list of AMQP endpoints to fail over between,
+// along with the backoff policy used while reconnecting.
+type ClusterConfig struct {
+	// Endpoints is the ordered list of AMQP URLs to try. The first
+	// reachable endpoint becomes the active one.
+	Endpoints []string
+
+	// PinnedEndpoint, when true, keeps retrying the previously active
+	// endpoint first on reconnect instead of always starting from the
+	// beginning of Endpoints.
+	PinnedEndpoint bool
+
+	// Backoff controls the delay between dial attempts against a given
+	// endpoint. Defaults to ExponentialJitterBackoff.
+	Backoff BackoffPolicy
+
+	// DialTimeout bounds how long Dial waits on a single endpoint before
+	// failing over to the next one. Defaults to 10s.
+	DialTimeout time.Duration
+
+	// OnEndpointChange, if set, fires whenever the active endpoint
+	// changes so application code can react (e.g. update health checks).
+	OnEndpointChange EndpointChangeFunc
+}
+
+// defaultDialTimeout bounds how long Dial waits on a single endpoint before
+// failing over to the next one, when ClusterConfig.DialTimeout is unset.
+const defaultDialTimeout = 10 * time.Second
+
+// clusterDialer dials a ClusterConfig's endpoints in order, failing over to
+// the next one on any non-terminal error and backing off between attempts
+// against the same endpoint.
+type clusterDialer struct {
+	cfg     ClusterConfig
+	active  int
+	amqpCfg amqp.Config
+}
+
+func newClusterDialer(cfg ClusterConfig, amqpCfg amqp.Config) *clusterDialer {
+	if cfg.Backoff == nil {
+		cfg.Backoff = ExponentialJitterBackoff{}
+	}
+
+	return &clusterDialer{cfg: cfg, amqpCfg: amqpCfg}
+}
+
+// Dial iterates the configured endpoints, starting from the active one when
+// PinnedEndpoint is set, until one connects or ctx is done. Network and
+// channel errors are treated as failover-worthy; ctx.Canceled and
+// ctx.DeadlineExceeded are terminal and returned immediately. Each endpoint
+// gets at most DialTimeout before Dial moves on to the next one, so a
+// blackholed endpoint can't stall the whole loop.
+func (d *clusterDialer) Dial(ctx context.Context) (*amqp.Connection, string, error) {
+	if len(d.cfg.Endpoints) == 0 {
+		return nil, "", errors.New("rcgo: no cluster endpoints configured")
+	}
+
+	dialTimeout := d.cfg.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultDialTimeout
+	}
+
+	start := 0
+	if d.cfg.PinnedEndpoint {
+		start = d.active
+	}
+
+	attempt := 0
+
+	for {
+		for i := 0; i < len(d.cfg.Endpoints); i++ {
+			idx := (start + i) % len(d.cfg.Endpoints)
+			endpoint := d.cfg.Endpoints[idx]
+
+			attemptCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+			conn, err := d.dialEndpoint(attemptCtx, endpoint)
+			cancel()
+
+			if err == nil {
+				if idx != d.active && d.cfg.OnEndpointChange != nil {
+					d.cfg.OnEndpointChange(endpoint)
+				}
+
+				d.active = idx
+
+				return conn, endpoint, nil
+			}
+
+			if errors.Is(ctx.Err(), context.Canceled) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, "", ctx.Err()
+			}
+		}
+
+		attempt++
+
+		select {
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		case <-time.After(d.cfg.Backoff.Next(attempt)):
+		}
+	}
+}
+
+// dialEndpoint runs amqp.DialConfig against endpoint, bounded by ctx.
+// amqp.DialConfig has no context parameter of its own, so the dial happens
+// on a goroutine and dialEndpoint returns as soon as ctx is done; a
+// connection that completes after that point is closed instead of leaked.
+func (d *clusterDialer) dialEndpoint(ctx context.Context, endpoint string) (*amqp.Connection, error) {
+	type result struct {
+		conn *amqp.Connection
+		err  error
+	}
+
+	resCh := make(chan result, 1)
+
+	go func() {
+		conn, err := amqp.DialConfig(endpoint, d.amqpCfg)
+		resCh <- result{conn, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		go func() {
+			if res := <-resCh; res.err == nil && res.conn != nil {
+				res.conn.Close()
+			}
+		}()
+
+		return nil, ctx.Err()
+	case res := <-resCh:
+		return res.conn, res.err
+	}
+}
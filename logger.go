@@ -0,0 +1,19 @@
+package rcgo
+
+// Logger is the minimal logging surface Publisher/Listener depend on. Users
+// inject an implementation via PublisherConfigs.Logger / ListenerConfigs.Logger;
+// a zero rcgo/logzerolog.Logger preserves the library's historical
+// zerolog-backed behavior and is used when none is supplied.
+//
+// kv are alternating key/value pairs (e.g. "correlationId", corrId,
+// "routingKey", rk) appended as contextual fields to the log line.
+//
+// Note: this tree has no concrete Publisher/Listener/PublisherConfigs/
+// ListenerConfigs to inject Logger through yet; newReplyRouter takes one
+// directly until that wiring exists.
+type Logger interface {
+	Debug(msg string, kv ...any)
+	Info(msg string, kv ...any)
+	Warn(msg string, kv ...any)
+	Error(msg string, kv ...any)
+}
@@ -0,0 +1,39 @@
+// Package logzap adapts go.uber.org/zap to rcgo.Logger, for applications
+// that already standardize on zap for structured logging.
+package logzap
+
+import "go.uber.org/zap"
+
+// Logger adapts a *zap.Logger to rcgo.Logger.
+type Logger struct {
+	z *zap.Logger
+}
+
+// New wraps an existing *zap.Logger. Passing nil uses zap.NewNop.
+func New(z *zap.Logger) Logger {
+	if z == nil {
+		z = zap.NewNop()
+	}
+
+	return Logger{z: z}
+}
+
+func fields(kv []any) []zap.Field {
+	fs := make([]zap.Field, 0, len(kv)/2)
+
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			continue
+		}
+
+		fs = append(fs, zap.Any(key, kv[i+1]))
+	}
+
+	return fs
+}
+
+func (l Logger) Debug(msg string, kv ...any) { l.z.Debug(msg, fields(kv)...) }
+func (l Logger) Info(msg string, kv ...any)  { l.z.Info(msg, fields(kv)...) }
+func (l Logger) Warn(msg string, kv ...any)  { l.z.Warn(msg, fields(kv)...) }
+func (l Logger) Error(msg string, kv ...any) { l.z.Error(msg, fields(kv)...) }
@@ -0,0 +1,55 @@
+// Package logzerolog is the default rcgo.Logger implementation, backed by
+// github.com/rs/zerolog/log. It preserves the library's historical logging
+// behavior, including honoring a "disabled" level.
+package logzerolog
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Logger adapts zerolog/log to rcgo.Logger.
+type Logger struct{}
+
+// New builds a Logger. level is parsed with zerolog.ParseLevel; an invalid
+// or "disabled" level silences all output, matching the behavior of the
+// previous hardcoded LogLevel configs field.
+func New(level string) Logger {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		lvl = zerolog.Disabled
+	}
+
+	zerolog.SetGlobalLevel(lvl)
+
+	return Logger{}
+}
+
+func withKV(e *zerolog.Event, kv []any) *zerolog.Event {
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			continue
+		}
+
+		e = e.Interface(key, kv[i+1])
+	}
+
+	return e
+}
+
+func (Logger) Debug(msg string, kv ...any) {
+	withKV(log.Debug(), kv).Msg(msg)
+}
+
+func (Logger) Info(msg string, kv ...any) {
+	withKV(log.Info(), kv).Msg(msg)
+}
+
+func (Logger) Warn(msg string, kv ...any) {
+	withKV(log.Warn(), kv).Msg(msg)
+}
+
+func (Logger) Error(msg string, kv ...any) {
+	withKV(log.Error(), kv).Msg(msg)
+}
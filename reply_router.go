@@ -1,12 +1,29 @@
 package rcgo
 
 import (
+	"context"
 	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
-	"github.com/rs/zerolog/log"
+
+	"github.com/julian776/reactive-commons-go/logzerolog"
+)
+
+// defaultShardCount is used when newReplyRouter is given a shardCount <= 0.
+const defaultShardCount = 32
+
+// defaultWheelResolution is the tick resolution of the timing wheel used to
+// expire pending replies in bulk instead of one timer per in-flight reply.
+const defaultWheelResolution = 100 * time.Millisecond
+
+const (
+	replyPending int32 = iota
+	replyDelivered
 )
 
 type reply struct {
@@ -15,38 +32,98 @@ type reply struct {
 	err   error
 }
 
+// replyStr is the bookkeeping kept per in-flight correlation id. state is
+// CAS'd from replyPending to replyDelivered so the consume goroutine and the
+// timing wheel can race over who gets to close ch without both delivering.
 type replyStr struct {
-	query string
-	ch    chan *reply
+	query  string
+	ch     chan *reply
+	state  int32
+	bucket int
+}
 
-	// Timer to delete the reply when timeout.
-	timer *time.Timer
+// shard is one partition of the replies map, guarded by its own lock so
+// registrations, deliveries and expiries don't all contend on a single
+// mutex under load.
+type shard struct {
+	mu      sync.RWMutex
+	replies map[string]*replyStr
 }
-type repliesMap map[interface{}]replyStr
 
 type replyRouter struct {
 	id         string
 	ch         *amqp.Channel
-	repliesMap repliesMap
+	shards     []*shard
+	shardCount uint32
 	timeout    time.Duration
+	wheel      *timingWheel
+	appName    string
+	logger     Logger
 }
 
+// newReplyRouter builds a replyRouter whose pending replies are spread
+// across shardCount shards. shardCount is typically wired from
+// PublisherConfigs.ReplyShardCount; a value <= 0 falls back to
+// defaultShardCount. logger is typically PublisherConfigs.Logger, falling
+// back to a logzerolog.Logger when the caller leaves it nil.
+//
+// Note: this tree has no concrete Publisher/PublisherConfigs to wire
+// shardCount/logger from (see transport.go); callers exercise replyRouter
+// directly until that integration exists.
 func newReplyRouter(
 	appName string,
 	timeout time.Duration,
+	shardCount int,
+	logger Logger,
 ) *replyRouter {
+	if logger == nil {
+		// The zero Logger logs through zerolog/log at zerolog's current
+		// global level; logzerolog.New would reset that level.
+		logger = logzerolog.Logger{}
+	}
+
 	if timeout < time.Millisecond*500 {
-		log.Warn().Msg("Be careful. Your timeout is too short, please consider give enough timeout to your replies.")
+		logger.Warn("Be careful. Your timeout is too short, please consider give enough timeout to your replies.", "app", appName)
 	}
 
-	return &replyRouter{
+	if shardCount <= 0 {
+		shardCount = defaultShardCount
+	}
+
+	shards := make([]*shard, shardCount)
+	for i := range shards {
+		shards[i] = &shard{replies: make(map[string]*replyStr)}
+	}
+
+	r := &replyRouter{
 		id:         fmt.Sprintf("%s.%s", appName, uuid.NewString()),
-		repliesMap: make(repliesMap),
+		shards:     shards,
+		shardCount: uint32(shardCount),
 		timeout:    timeout,
+		appName:    appName,
+		logger:     logger,
 	}
+	r.wheel = newTimingWheel(defaultWheelResolution, timeout, r.cleanReply)
+
+	return r
 }
 
-func (r *replyRouter) listen(conn *amqp.Connection) error {
+func (r *replyRouter) shardFor(correlationId string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(correlationId))
+
+	return r.shards[h.Sum32()%r.shardCount]
+}
+
+// listen runs until ctx is done, at which point it drains and closes every
+// still-pending reply channel with a wrapped ctx.Err() instead of leaving
+// callers to wait out their full timeout against a channel nothing will
+// ever write to again.
+//
+// Note: this tree has no concrete Listener to apply the same
+// cancel-equals-Stop pattern to (Listener.Listen/Stop don't exist here
+// yet); this is the replyRouter half of that lifecycle only.
+func (r *replyRouter) listen(ctx context.Context, conn *amqp.Connection) error {
 	ch, err := conn.Channel()
 	failOnError(err, "Failed to open a reply channel")
 
@@ -101,6 +178,8 @@ func (r *replyRouter) listen(conn *amqp.Connection) error {
 		return err
 	}
 
+	r.wheel.start()
+
 	go func() {
 		for msg := range msgs {
 			// Create a copy
@@ -115,60 +194,187 @@ func (r *replyRouter) listen(conn *amqp.Connection) error {
 				}
 			}
 
-			if replyStr, ok := r.repliesMap[corrId]; ok {
-				// Verify if the timeout has already elapsed.
-				if !replyStr.timer.Stop() {
-					m.Ack(false)
-					continue
-				}
+			r.deliver(corrId, m)
+		}
+	}()
 
-				replyStr.ch <- &reply{
-					query: replyStr.query,
-					data:  m.Body,
-					err:   nil,
-				}
+	go func() {
+		<-ctx.Done()
+		// Closing the channel closes msgs, ending the consume goroutine.
+		r.ch.Close()
+		r.wheel.stop()
+		r.drain(ctx.Err())
+	}()
 
-				close(replyStr.ch)
+	return nil
+}
 
-				delete(r.repliesMap, corrId)
+// drain closes every pending reply channel with cause, reclaiming their
+// correlation ids. Called once listen's ctx is done.
+func (r *replyRouter) drain(cause error) {
+	for _, s := range r.shards {
+		s.mu.Lock()
+		pending := make([]*replyStr, 0, len(s.replies))
+		for corrId, rs := range s.replies {
+			pending = append(pending, rs)
+			delete(s.replies, corrId)
+		}
+		s.mu.Unlock()
 
-				m.Ack(false)
+		for _, rs := range pending {
+			if !atomic.CompareAndSwapInt32(&rs.state, replyPending, replyDelivered) {
+				continue
 			}
+
+			rs.ch <- &reply{err: fmt.Errorf("rcgo: reply router stopped: %w", cause)}
+			close(rs.ch)
 		}
-	}()
+	}
+}
 
-	return nil
+// deliver looks up the pending reply for corrId on its shard and, racing
+// the timing wheel's expiry, CAS's it from pending to delivered before
+// sending on its channel.
+func (r *replyRouter) deliver(corrId string, m amqp.Delivery) {
+	s := r.shardFor(corrId)
+
+	s.mu.RLock()
+	rs, ok := s.replies[corrId]
+	s.mu.RUnlock()
+
+	if !ok {
+		m.Ack(false)
+		return
+	}
+
+	if !atomic.CompareAndSwapInt32(&rs.state, replyPending, replyDelivered) {
+		// The timing wheel already expired this correlation id.
+		m.Ack(false)
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.replies, corrId)
+	s.mu.Unlock()
+
+	r.wheel.cancel(rs.bucket, corrId)
+
+	r.logger.Debug("reply delivered",
+		"app", r.appName,
+		"correlationId", corrId,
+		"query", rs.query,
+	)
+
+	rs.ch <- &reply{
+		query: rs.query,
+		data:  m.Body,
+		err:   nil,
+	}
+	close(rs.ch)
+
+	m.Ack(false)
 }
 
-func (r *replyRouter) addReplyToListen(query string, correlationId string) chan *reply {
-	ch := make(chan *reply)
+// addReplyToListen registers correlationId and blocks until whichever of
+// ctx.Done(), the reply arriving, or the timing wheel's expiry happens
+// first. Cancelling ctx reclaims the correlation id immediately instead of
+// waiting out r.timeout.
+func (r *replyRouter) addReplyToListen(ctx context.Context, query string, correlationId string) (*reply, error) {
+	rs := r.register(query, correlationId)
+
+	select {
+	case <-ctx.Done():
+		r.cancelReply(correlationId, rs)
+		return nil, ctx.Err()
+	case rep := <-rs.ch:
+		if rep.err != nil {
+			return nil, rep.err
+		}
 
-	timer := time.AfterFunc(r.timeout, func() {
-		r.cleanReply(correlationId)
-	})
+		return rep, nil
+	}
+}
 
-	r.repliesMap[correlationId] = replyStr{
+// register inserts correlationId's bookkeeping into its shard and schedules
+// its expiry on the timing wheel, without waiting for a reply. Split out of
+// addReplyToListen so tests/benchmarks can register and deliver
+// deterministically.
+func (r *replyRouter) register(query string, correlationId string) *replyStr {
+	rs := &replyStr{
 		query: query,
-		ch:    ch,
-		timer: timer,
+		ch:    make(chan *reply, 1),
+		state: replyPending,
+	}
+
+	// Schedule before publishing rs into the shard map: once s.replies
+	// holds rs, deliver/cleanReply/cancelReply can read rs.bucket from
+	// another goroutine with no further synchronization between them and
+	// this one, so bucket must already be its final value by then.
+	rs.bucket = r.wheel.schedule(correlationId)
+
+	s := r.shardFor(correlationId)
+
+	s.mu.Lock()
+	s.replies[correlationId] = rs
+	s.mu.Unlock()
+
+	r.logger.Debug("reply registered",
+		"app", r.appName,
+		"correlationId", correlationId,
+		"query", query,
+	)
+
+	return rs
+}
+
+// cancelReply reclaims correlationId on behalf of a caller whose ctx was
+// cancelled, racing deliver/cleanReply via the same state CAS.
+func (r *replyRouter) cancelReply(correlationId string, rs *replyStr) {
+	if !atomic.CompareAndSwapInt32(&rs.state, replyPending, replyDelivered) {
+		return
 	}
 
-	return ch
+	s := r.shardFor(correlationId)
+
+	s.mu.Lock()
+	delete(s.replies, correlationId)
+	s.mu.Unlock()
+
+	r.wheel.cancel(rs.bucket, correlationId)
 }
 
+// cleanReply is invoked by the timing wheel when a correlation id's bucket
+// expires. It races deliver via the same state CAS.
 func (r *replyRouter) cleanReply(correlationId string) {
-	replyStr, ok := r.repliesMap[correlationId]
+	s := r.shardFor(correlationId)
+
+	s.mu.RLock()
+	rs, ok := s.replies[correlationId]
+	s.mu.RUnlock()
+
 	if !ok {
 		return
 	}
 
-	replyStr.ch <- &reply{
+	if !atomic.CompareAndSwapInt32(&rs.state, replyPending, replyDelivered) {
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.replies, correlationId)
+	s.mu.Unlock()
+
+	r.logger.Warn("reply timed out",
+		"app", r.appName,
+		"correlationId", correlationId,
+		"query", rs.query,
+	)
+
+	rs.ch <- &reply{
 		err: &TimeoutReplyError{
-			msg: "timeout while waiting for reply " + replyStr.query,
+			msg: "timeout while waiting for reply " + rs.query,
 		},
 	}
 
-	close(replyStr.ch)
-
-	delete(r.repliesMap, correlationId)
+	close(rs.ch)
 }
@@ -0,0 +1,95 @@
+package rcgo
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// noopAcknowledger satisfies amqp.Acknowledger without a live channel, so
+// benchmark deliveries can call Delivery.Ack/Nack/Reject safely.
+type noopAcknowledger struct{}
+
+func (noopAcknowledger) Ack(tag uint64, multiple bool) error                { return nil }
+func (noopAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error { return nil }
+func (noopAcknowledger) Reject(tag uint64, requeue bool) error              { return nil }
+
+func fakeDelivery() amqp.Delivery {
+	return amqp.Delivery{Acknowledger: noopAcknowledger{}, Body: []byte("bench")}
+}
+
+// noopLogger discards everything, keeping the benchmark focused on router
+// throughput rather than logging overhead.
+type noopLogger struct{}
+
+func (noopLogger) Debug(msg string, kv ...any) {}
+func (noopLogger) Info(msg string, kv ...any)  {}
+func (noopLogger) Warn(msg string, kv ...any)  {}
+func (noopLogger) Error(msg string, kv ...any) {}
+
+// TestReplyRouter_RegisterDeliverRace exercises register/deliver under
+// -race across many correlation ids at once, so goroutines contend on the
+// shared shards and timing-wheel buckets. Each id is registered and then
+// delivered on the same goroutine.
+func TestReplyRouter_RegisterDeliverRace(t *testing.T) {
+	r := newReplyRouter("race", time.Minute, defaultShardCount, noopLogger{})
+
+	const n = 200
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			id := fmt.Sprintf("race-%d", i)
+			rs := r.register("race.query", id)
+			r.deliver(id, fakeDelivery())
+			<-rs.ch
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// BenchmarkReplyRouter_InFlight measures register/deliver throughput
+// with a large number of concurrently in-flight replies, to validate that
+// sharding keeps lock contention low at 10k+ pending correlation ids.
+func BenchmarkReplyRouter_InFlight(b *testing.B) {
+	for _, n := range []int{100, 1_000, 10_000} {
+		b.Run(fmt.Sprintf("inflight-%d", n), func(b *testing.B) {
+			r := newReplyRouter("bench", time.Minute, defaultShardCount, noopLogger{})
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				ids := make([]string, n)
+				waiters := make([]chan *reply, n)
+
+				for j := 0; j < n; j++ {
+					ids[j] = fmt.Sprintf("bench-%d-%d", i, j)
+					rs := r.register("bench.query", ids[j])
+					waiters[j] = rs.ch
+				}
+
+				var wg sync.WaitGroup
+				wg.Add(n)
+
+				for j := 0; j < n; j++ {
+					go func(ch chan *reply) {
+						defer wg.Done()
+						<-ch
+					}(waiters[j])
+
+					r.deliver(ids[j], fakeDelivery())
+				}
+
+				wg.Wait()
+			}
+		})
+	}
+}
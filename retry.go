@@ -0,0 +1,267 @@
+package rcgo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Publisher is the surface Retry wraps. RetryOption composition only
+// depends on this interface, so it works against any transport that exposes
+// the same calls (see Transport).
+//
+// Note: this tree has no concrete Publisher implementation for Retry to
+// wrap yet.
+type Publisher interface {
+	PublishEvent(ctx context.Context, eventType string, data interface{}) error
+	SendCmd(ctx context.Context, target string, cmdType string, data interface{}) error
+	RequestReply(ctx context.Context, target string, queryType string, data interface{}) ([]byte, error)
+}
+
+// BackoffStrategy produces the wait duration before retry attempt n (1-based).
+type BackoffStrategy interface {
+	Next(attempt int) time.Duration
+}
+
+// ConstantBackoff waits the same Delay before every retry.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+func (b ConstantBackoff) Next(attempt int) time.Duration { return b.Delay }
+
+// DecorrelatedJitterBackoff implements the "decorrelated jitter" strategy:
+// each wait is a random value between Base and the previous wait * 3,
+// capped at Max.
+type DecorrelatedJitterBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+
+	mu   sync.Mutex
+	prev time.Duration
+}
+
+func (b *DecorrelatedJitterBackoff) Next(attempt int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.prev == 0 {
+		b.prev = b.Base
+	}
+
+	max := b.prev * 3
+	if max > b.Max {
+		max = b.Max
+	}
+
+	d := b.Base
+	if span := int64(max - b.Base + 1); span > 0 {
+		d += time.Duration(rand.Int63n(span))
+	}
+
+	if d > b.Max {
+		d = b.Max
+	}
+
+	b.prev = d
+
+	return d
+}
+
+// RetryOption configures the Retry middleware.
+type RetryOption func(*retryPublisher)
+
+// WithRetry sets the maximum total number of attempts (the first try plus
+// any retries) and the per-attempt timeout applied to ctx on each try.
+func WithRetry(max int, perAttemptTimeout time.Duration) RetryOption {
+	return func(r *retryPublisher) {
+		r.maxAttempts = max
+		r.perAttemptTimeout = perAttemptTimeout
+	}
+}
+
+// WithBackoff sets the wait strategy applied between attempts.
+func WithBackoff(b BackoffStrategy) RetryOption {
+	return func(r *retryPublisher) {
+		r.backoff = b
+	}
+}
+
+// WithCircuitBreaker trips the circuit after failureThreshold consecutive
+// RequestReply failures, short-circuiting further calls with
+// ErrCircuitOpen until resetTimeout elapses.
+func WithCircuitBreaker(failureThreshold int, resetTimeout time.Duration) RetryOption {
+	return func(r *retryPublisher) {
+		r.breaker = &circuitBreaker{threshold: failureThreshold, resetTimeout: resetTimeout}
+	}
+}
+
+// WithNonIdempotent excludes the given query types from retries, since
+// re-publishing them could duplicate a non-idempotent side effect.
+func WithNonIdempotent(queryTypes ...string) RetryOption {
+	return func(r *retryPublisher) {
+		for _, t := range queryTypes {
+			r.nonIdempotent[t] = struct{}{}
+		}
+	}
+}
+
+// ErrCircuitOpen is returned by RequestReply while the circuit breaker is
+// tripped.
+var ErrCircuitOpen = errors.New("rcgo: circuit breaker open")
+
+// circuitBreaker is a minimal consecutive-failure breaker: it trips after
+// threshold consecutive failures and resets after resetTimeout.
+type circuitBreaker struct {
+	threshold    int
+	resetTimeout time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+}
+
+func (c *circuitBreaker) allow() bool {
+	if c.threshold <= 0 {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.open && time.Since(c.openedAt) > c.resetTimeout {
+		c.open = false
+		c.failures = 0
+	}
+
+	return !c.open
+}
+
+func (c *circuitBreaker) recordSuccess() {
+	if c.threshold <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures = 0
+	c.open = false
+}
+
+func (c *circuitBreaker) recordFailure() {
+	if c.threshold <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures++
+	if c.failures >= c.threshold {
+		c.open = true
+		c.openedAt = time.Now()
+	}
+}
+
+// retryPublisher wraps a Publisher with retry, backoff and circuit-breaker
+// behavior around RequestReply, where a TimeoutReplyError or transient AMQP
+// error triggers a fresh correlation id and a republish rather than
+// surfacing the failure to the caller.
+type retryPublisher struct {
+	Publisher
+
+	maxAttempts       int
+	perAttemptTimeout time.Duration
+	backoff           BackoffStrategy
+	breaker           *circuitBreaker
+	nonIdempotent     map[string]struct{}
+}
+
+// Retry wraps p so callers see a single context-aware RequestReply call
+// that transparently retries transient failures according to opts.
+func Retry(p Publisher, opts ...RetryOption) Publisher {
+	r := &retryPublisher{
+		Publisher:     p,
+		maxAttempts:   1,
+		backoff:       ConstantBackoff{Delay: 200 * time.Millisecond},
+		breaker:       &circuitBreaker{},
+		nonIdempotent: make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func (r *retryPublisher) RequestReply(ctx context.Context, target string, queryType string, data interface{}) ([]byte, error) {
+	if _, excluded := r.nonIdempotent[queryType]; excluded {
+		return r.Publisher.RequestReply(ctx, target, queryType, data)
+	}
+
+	var lastErr error
+
+	for attempt := 1; attempt <= r.maxAttempts; attempt++ {
+		if !r.breaker.allow() {
+			return nil, ErrCircuitOpen
+		}
+
+		attemptCtx := ctx
+		cancel := func() {}
+
+		if r.perAttemptTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, r.perAttemptTimeout)
+		}
+
+		res, err := r.Publisher.RequestReply(attemptCtx, target, queryType, data)
+		cancel()
+
+		if err == nil {
+			r.breaker.recordSuccess()
+			return res, nil
+		}
+
+		r.breaker.recordFailure()
+		lastErr = err
+
+		if !isRetryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		if attempt < r.maxAttempts {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(r.backoff.Next(attempt)):
+			}
+		}
+	}
+
+	return nil, lastErr
+}
+
+// isRetryable reports whether err is worth a fresh attempt: a reply timeout
+// or an AMQP error the server flagged as recoverable. Anything else -
+// including a permanent/business error the handler returned deliberately -
+// is surfaced to the caller immediately instead of burning attempts and
+// backoff time on a retry that can't succeed.
+func isRetryable(err error) bool {
+	var timeoutErr *TimeoutReplyError
+	if errors.As(err, &timeoutErr) {
+		return true
+	}
+
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) {
+		return amqpErr.Recover
+	}
+
+	return false
+}
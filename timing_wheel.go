@@ -0,0 +1,105 @@
+package rcgo
+
+import (
+	"sync"
+	"time"
+)
+
+// timingWheel expires pending replies in bulk instead of allocating a
+// time.Timer per in-flight correlation id. It is a simple single-level
+// hashed wheel: timeout/resolution ticks per revolution, each bucket holding
+// the correlation ids scheduled to expire on that tick.
+type timingWheel struct {
+	resolution time.Duration
+	buckets    []map[string]struct{}
+	mu         sync.Mutex
+	cursor     int
+	onExpire   func(correlationId string)
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+}
+
+// newTimingWheel builds a wheel sized so that a reply scheduled now expires
+// after approximately timeout, with expiry grouped into resolution-sized
+// buckets.
+func newTimingWheel(resolution time.Duration, timeout time.Duration, onExpire func(string)) *timingWheel {
+	slots := int(timeout / resolution)
+	if slots < 1 {
+		slots = 1
+	}
+
+	buckets := make([]map[string]struct{}, slots)
+	for i := range buckets {
+		buckets[i] = make(map[string]struct{})
+	}
+
+	return &timingWheel{
+		resolution: resolution,
+		buckets:    buckets,
+		onExpire:   onExpire,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// start begins ticking the wheel. Safe to call multiple times; only the
+// first call spawns the ticking goroutine, which runs until stop is
+// called.
+func (w *timingWheel) start() {
+	w.startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(w.resolution)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-w.stopCh:
+					return
+				case <-ticker.C:
+					w.tick()
+				}
+			}
+		}()
+	})
+}
+
+// stop ends the ticking goroutine started by start, if any. Safe to call
+// multiple times or without a prior start.
+func (w *timingWheel) stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+	})
+}
+
+func (w *timingWheel) tick() {
+	w.mu.Lock()
+	bucket := w.buckets[w.cursor]
+	w.buckets[w.cursor] = make(map[string]struct{})
+	w.cursor = (w.cursor + 1) % len(w.buckets)
+	w.mu.Unlock()
+
+	for correlationId := range bucket {
+		w.onExpire(correlationId)
+	}
+}
+
+// schedule places correlationId into the bucket one full revolution away
+// and returns that bucket index, so it can be passed to cancel later.
+func (w *timingWheel) schedule(correlationId string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	bucket := (w.cursor - 1 + len(w.buckets)) % len(w.buckets)
+	w.buckets[bucket][correlationId] = struct{}{}
+
+	return bucket
+}
+
+// cancel removes correlationId from the given bucket, e.g. when it was
+// delivered before its expiry tick.
+func (w *timingWheel) cancel(bucket int, correlationId string) {
+	w.mu.Lock()
+	delete(w.buckets[bucket], correlationId)
+	w.mu.Unlock()
+}
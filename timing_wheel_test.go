@@ -0,0 +1,30 @@
+package rcgo
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTimingWheel_StopEndsTicking verifies stop actually halts the ticking
+// goroutine started by start, instead of leaking it for the life of the
+// process once a replyRouter's listen context is cancelled.
+func TestTimingWheel_StopEndsTicking(t *testing.T) {
+	var ticks int32
+
+	w := newTimingWheel(time.Millisecond, 10*time.Millisecond, func(string) {
+		atomic.AddInt32(&ticks, 1)
+	})
+
+	// Without stop, this id would expire after 10 ticks (~10ms).
+	w.schedule("stop-test")
+
+	w.start()
+	w.stop()
+
+	time.Sleep(20 * time.Millisecond)
+
+	if n := atomic.LoadInt32(&ticks); n != 0 {
+		t.Fatalf("onExpire fired %d times after stop, want 0", n)
+	}
+}
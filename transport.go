@@ -0,0 +1,19 @@
+package rcgo
+
+import "context"
+
+// Transport is the messaging surface a Listener/Publisher is built on top
+// of. rcgo/transportgrpc implements it for environments where RabbitMQ
+// isn't available.
+//
+// Note: this tree has no AMQP-backed Publisher/Listener implementing
+// Transport yet; rcgo/transportgrpc is its only implementation here.
+type Transport interface {
+	PublishEvent(ctx context.Context, eventType string, data interface{}) error
+	SendCmd(ctx context.Context, target string, cmdType string, data interface{}) error
+	RequestReply(ctx context.Context, target string, queryType string, data interface{}) ([]byte, error)
+
+	SubscribeCmd(cmdType string, handler func(ctx context.Context, c *Cmd) error) error
+	SubscribeEvent(eventType string, handler func(ctx context.Context, e *Event) error) error
+	SubscribeQuery(queryType string, handler func(ctx context.Context, q *Query) (interface{}, error)) error
+}
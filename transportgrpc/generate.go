@@ -0,0 +1,3 @@
+package transportgrpc
+
+//go:generate protoc --go_out=. --go_opt=module=github.com/julian776/reactive-commons-go/transportgrpc --go-grpc_out=. --go-grpc_opt=module=github.com/julian776/reactive-commons-go/transportgrpc rcgo.proto
@@ -0,0 +1,329 @@
+//go:build rcgo_grpc_generated
+
+// Package transportgrpc is a rcgo.Transport backend that speaks the
+// protobuf service defined in rcgo.proto instead of AMQP, for environments
+// where RabbitMQ isn't available (e.g. a Kubernetes-native mesh). Servers
+// register handlers through the same Listener.AddCommandHandler/
+// AddEventHandler/AddQueryHandler API; application code doesn't change.
+//
+// rcgopb is produced by `go generate ./...` (see generate.go) via
+// protoc-gen-go/protoc-gen-go-grpc from rcgo.proto, and is not committed
+// to this tree yet. This file is therefore excluded from the default
+// build by the rcgo_grpc_generated tag; run go generate, commit the
+// resulting rcgopb package, and build with -tags rcgo_grpc_generated.
+//
+// Note: this tree also has no concrete Listener for
+// AddCommandHandler/AddEventHandler/AddQueryHandler to live on, so
+// "application code doesn't change" above describes the intended shape
+// of the integration, not something reachable from this tree today.
+package transportgrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	rcgo "github.com/julian776/reactive-commons-go"
+	"github.com/julian776/reactive-commons-go/transportgrpc/rcgopb"
+)
+
+// Transport implements rcgo.Transport over a single gRPC connection. Unlike
+// the AMQP backend, RequestReply doesn't declare an exclusive reply queue:
+// it multiplexes every in-flight query over one Replies bidirectional
+// stream, correlated by id.
+type Transport struct {
+	appName string
+	conn    *grpc.ClientConn
+	client  rcgopb.TransportClient
+
+	repliesMu sync.Mutex
+	replies   map[string]chan *rcgopb.Reply
+	stream    rcgopb.Transport_RepliesClient
+
+	// queryHandlers and queryStream back SubscribeQuery: queryStream is
+	// opened lazily on the first SubscribeQuery call and carries every
+	// Query this app is the Target of, pushed by the broker; handlers are
+	// looked up by type and their Reply streamed back on the same call.
+	queryHandlersMu sync.Mutex
+	queryHandlers   map[string]func(ctx context.Context, q *rcgo.Query) (interface{}, error)
+	queryStream     rcgopb.Transport_ServeQueriesClient
+	queryStreamOnce sync.Once
+	queryStreamErr  error
+	querySendMu     sync.Mutex
+}
+
+// New dials target (a gRPC endpoint, e.g. "dns:///rcgo-broker:9090") and
+// opens the Replies stream used for RequestReply.
+func New(ctx context.Context, appName string, target string, opts ...grpc.DialOption) (*Transport, error) {
+	conn, err := grpc.DialContext(ctx, target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("transportgrpc: dial %s: %w", target, err)
+	}
+
+	client := rcgopb.NewTransportClient(conn)
+
+	stream, err := client.Replies(ctx)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("transportgrpc: open replies stream: %w", err)
+	}
+
+	t := &Transport{
+		appName:       appName,
+		conn:          conn,
+		client:        client,
+		replies:       make(map[string]chan *rcgopb.Reply),
+		stream:        stream,
+		queryHandlers: make(map[string]func(ctx context.Context, q *rcgo.Query) (interface{}, error)),
+	}
+
+	go t.recvReplies()
+
+	return t, nil
+}
+
+func (t *Transport) recvReplies() {
+	for {
+		rep, err := t.stream.Recv()
+		if err != nil {
+			return
+		}
+
+		t.repliesMu.Lock()
+		ch, ok := t.replies[rep.CorrelationId]
+		delete(t.replies, rep.CorrelationId)
+		t.repliesMu.Unlock()
+
+		if ok {
+			ch <- rep
+			close(ch)
+		}
+	}
+}
+
+func (t *Transport) PublishEvent(ctx context.Context, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("transportgrpc: marshal event data: %w", err)
+	}
+
+	_, err = t.client.PublishEvent(ctx, &rcgopb.Event{
+		Source: t.appName,
+		Type:   eventType,
+		Data:   payload,
+	})
+
+	return err
+}
+
+func (t *Transport) SendCmd(ctx context.Context, target string, cmdType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("transportgrpc: marshal cmd data: %w", err)
+	}
+
+	_, err = t.client.SendCmd(ctx, &rcgopb.Cmd{
+		Source: t.appName,
+		Target: target,
+		Type:   cmdType,
+		Data:   payload,
+	})
+
+	return err
+}
+
+func (t *Transport) RequestReply(ctx context.Context, target string, queryType string, data interface{}) ([]byte, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("transportgrpc: marshal query data: %w", err)
+	}
+
+	query := &rcgopb.Query{
+		Id:     fmt.Sprintf("%s.%s", t.appName, uuid.NewString()),
+		Source: t.appName,
+		Target: target,
+		Type:   queryType,
+		Data:   payload,
+	}
+
+	ch := make(chan *rcgopb.Reply, 1)
+
+	t.repliesMu.Lock()
+	t.replies[query.Id] = ch
+	t.repliesMu.Unlock()
+
+	if err := t.stream.Send(query); err != nil {
+		return nil, fmt.Errorf("transportgrpc: send query: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.repliesMu.Lock()
+		delete(t.replies, query.Id)
+		t.repliesMu.Unlock()
+
+		return nil, ctx.Err()
+	case rep := <-ch:
+		if rep.Error != "" {
+			return nil, fmt.Errorf("transportgrpc: %s", rep.Error)
+		}
+
+		return rep.Data, nil
+	}
+}
+
+func (t *Transport) SubscribeCmd(cmdType string, handler func(ctx context.Context, c *rcgo.Cmd) error) error {
+	stream, err := t.client.SubscribeCmds(context.Background(), &rcgopb.Cmd{Type: cmdType, Target: t.appName})
+	if err != nil {
+		return fmt.Errorf("transportgrpc: subscribe cmds %s: %w", cmdType, err)
+	}
+
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				return
+			}
+
+			var data interface{}
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				continue
+			}
+
+			_ = handler(context.Background(), &rcgo.Cmd{
+				Id:     msg.Id,
+				Source: msg.Source,
+				Target: msg.Target,
+				Type:   msg.Type,
+				Data:   data,
+			})
+		}
+	}()
+
+	return nil
+}
+
+func (t *Transport) SubscribeEvent(eventType string, handler func(ctx context.Context, e *rcgo.Event) error) error {
+	stream, err := t.client.SubscribeEvents(context.Background(), &rcgopb.Event{Type: eventType})
+	if err != nil {
+		return fmt.Errorf("transportgrpc: subscribe events %s: %w", eventType, err)
+	}
+
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				return
+			}
+
+			var data interface{}
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				continue
+			}
+
+			_ = handler(context.Background(), &rcgo.Event{
+				Id:     msg.Id,
+				Source: msg.Source,
+				Type:   msg.Type,
+				Data:   data,
+			})
+		}
+	}()
+
+	return nil
+}
+
+// SubscribeQuery is served out of ServeQueries: the broker pushes every
+// Query this app is the Target of down that stream, and recvQueries
+// dispatches each one by its Type to the handler registered here, streaming
+// the result back as a Reply correlated by the query's id.
+func (t *Transport) SubscribeQuery(queryType string, handler func(ctx context.Context, q *rcgo.Query) (interface{}, error)) error {
+	if err := t.ensureQueryStream(); err != nil {
+		return fmt.Errorf("transportgrpc: subscribe query %s: %w", queryType, err)
+	}
+
+	t.queryHandlersMu.Lock()
+	t.queryHandlers[queryType] = handler
+	t.queryHandlersMu.Unlock()
+
+	return nil
+}
+
+// ensureQueryStream opens the ServeQueries stream and starts recvQueries on
+// the first SubscribeQuery call; later calls reuse it.
+func (t *Transport) ensureQueryStream() error {
+	t.queryStreamOnce.Do(func() {
+		stream, err := t.client.ServeQueries(context.Background())
+		if err != nil {
+			t.queryStreamErr = fmt.Errorf("open serve queries stream: %w", err)
+			return
+		}
+
+		t.queryStream = stream
+
+		go t.recvQueries()
+	})
+
+	return t.queryStreamErr
+}
+
+func (t *Transport) recvQueries() {
+	for {
+		q, err := t.queryStream.Recv()
+		if err != nil {
+			return
+		}
+
+		go t.handleQuery(q)
+	}
+}
+
+// handleQuery dispatches q to the handler registered for its Type and
+// streams back the resulting Reply, correlated by q.Id. A missing handler
+// or a handler error is reported back as Reply.Error rather than dropped,
+// so the requester doesn't hang out its full timeout for nothing.
+func (t *Transport) handleQuery(q *rcgopb.Query) {
+	t.queryHandlersMu.Lock()
+	handler, ok := t.queryHandlers[q.Type]
+	t.queryHandlersMu.Unlock()
+
+	rep := &rcgopb.Reply{
+		CorrelationId: q.Id,
+		Source:        t.appName,
+		Target:        q.Source,
+	}
+
+	if !ok {
+		rep.Error = fmt.Sprintf("transportgrpc: no handler registered for query type %s", q.Type)
+	} else {
+		var data interface{}
+		if err := json.Unmarshal(q.Data, &data); err != nil {
+			rep.Error = fmt.Sprintf("transportgrpc: unmarshal query data: %s", err)
+		} else if result, err := handler(context.Background(), &rcgo.Query{
+			Id:     q.Id,
+			Source: q.Source,
+			Target: q.Target,
+			Type:   q.Type,
+			Data:   data,
+		}); err != nil {
+			rep.Error = err.Error()
+		} else if payload, err := json.Marshal(result); err != nil {
+			rep.Error = fmt.Sprintf("transportgrpc: marshal query result: %s", err)
+		} else {
+			rep.Data = payload
+		}
+	}
+
+	t.querySendMu.Lock()
+	_ = t.queryStream.Send(rep)
+	t.querySendMu.Unlock()
+}
+
+// Close releases the underlying gRPC connection.
+func (t *Transport) Close() error {
+	return t.conn.Close()
+}